@@ -5,49 +5,53 @@ import (
 	"fmt"
 	"net/http"
 	"net/mail"
-	"time"
 
 	"mvdan.cc/xurls/v2"
 )
 
 type RadarItemsStorageService interface {
-	// Store a new radar item.
-	Create(ctx context.Context, m RadarItem) error
-	// Delete a radar item by numerical id.
-	Delete(ctx context.Context, id int64) error
-	// Get a radar item by numerical id.
-	Get(ctx context.Context, id int64) (RadarItem, error)
-	// List radar items by numerical id.
-	List(ctx context.Context, limit int) ([]RadarItem, error)
+	// Store a new radar item for tenantID.
+	Create(ctx context.Context, tenantID string, m RadarItem) error
+	// Delete a radar item by numerical id, scoped to tenantID.
+	Delete(ctx context.Context, tenantID string, id int64) error
+	// Get a radar item by numerical id, scoped to tenantID.
+	Get(ctx context.Context, tenantID string, id int64) (RadarItem, error)
+	// List radar items belonging to tenantID.
+	List(ctx context.Context, tenantID string, limit int) ([]RadarItem, error)
 	// Shut down the storage service gracefully.
 	Shutdown(ctx context.Context)
 }
 
-func NewEmailHandler(radarItemsService RadarItemsStorageService, mailgunService MailgunService, allowedSenders []string, debug bool) EmailHandler {
+// Submitter turns an inbound HTTP request into one or more createRequests
+// on a shared Queue and acknowledges the caller. EmailHandler, along with
+// the Slack, rageshake, and Mastodon handlers, all implement Submitter so
+// a single radar deployment can accept submissions from any of them.
+type Submitter interface {
+	http.Handler
+}
+
+func NewEmailHandler(queue *Queue, mailgunService MailgunService, tenants *TenantDirectory, debug bool) EmailHandler {
 	return EmailHandler{
-		AllowedSenders: allowedSenders,
-		Debug:          debug,
-		RadarItems:     radarItemsService,
-		Mailgun:        mailgunService,
-		CreateQueue:    make(chan createRequest, 10),
+		Tenants: tenants,
+		Debug:   debug,
+		Queue:   queue,
+		Mailgun: mailgunService,
 	}
 }
 
 type EmailHandler struct {
-	// Email addresses that must be in the "From" section of the message.
-	AllowedSenders []string
+	// Tenants resolves a verified sender address to its Tenant and
+	// enforces its rate limit.
+	Tenants *TenantDirectory
 
 	// Enable debug logging.
 	Debug bool
 
-	// RadarItem service
-	RadarItems RadarItemsStorageService
+	// The shared ingestion queue.
+	Queue *Queue
 
 	// Mailgun service, used for sending email replies
 	Mailgun MailgunService
-
-	// The queue
-	CreateQueue chan createRequest
 }
 
 type createRequest struct {
@@ -58,42 +62,14 @@ type createRequest struct {
 	subject string
 
 	url string
-}
-
-// Start polls on the CreateQueue and runs
-func (h EmailHandler) Start() {
-	for req := range h.CreateQueue {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		if err := h.RadarItems.Create(ctx, RadarItem{URL: req.url}); err != nil {
-			Printf("error saving '%s': %#v %+v", req.url, err, err)
-			h.Mailgun.SendReply(req, "Could not save "+req.url+" to the radar: "+err.Error())
-		} else {
-			h.Mailgun.SendReply(req, "Added "+req.url+" to the radar.")
-			Printf("saved url=%s to database", req.url)
-		}
-		cancel()
-	}
-}
-
-func (h EmailHandler) Shutdown(ctx context.Context) {
-	close(h.CreateQueue)
-	h.RadarItems.Shutdown(ctx)
-}
-
-func (h EmailHandler) IsAllowedSender(sender string) bool {
-	email, err := mail.ParseAddress(sender)
-	if err != nil {
-		Printf("could not process sender '%s': %#v", sender, err)
-		return false
-	}
 
-	for _, allowedSender := range h.AllowedSenders {
-		if allowedSender == email.Address {
-			return true
-		}
-	}
+	// tenantID scopes this request's storage reads/writes and picks
+	// which Tenant's publisher/mention apply to its digest.
+	tenantID string
 
-	return false
+	// reply delivers a human-readable outcome message back to whoever
+	// submitted this request, over whatever channel they submitted it on.
+	reply func(message string)
 }
 
 func (h EmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -103,7 +79,16 @@ func (h EmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if sender := r.FormValue("From"); !h.IsAllowedSender(sender) {
+	sender := r.FormValue("From")
+	senderEmail, err := mail.ParseAddress(sender)
+	if err != nil {
+		Printf("could not process sender '%s': %#v", sender, err)
+		http.Error(w, "could not process sender: "+sender, http.StatusBadRequest)
+		return
+	}
+
+	tenant, ok := h.Tenants.Lookup(senderEmail.Address)
+	if !ok {
 		Println("not an allowed sender: ", sender)
 		http.Error(w, "not an allowed sender: "+sender, http.StatusUnauthorized)
 		return
@@ -130,14 +115,34 @@ func (h EmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Printf("form: %#v", r.Form)
 	}
 
+	added := 0
 	for _, url := range urls {
-		h.CreateQueue <- createRequest{
-			fromEmail: r.FormValue("From"),
+		if !h.Tenants.Allow(tenant.ID) {
+			Printf("rate limit exceeded for tenant %s, dropping %s", tenant.ID, url)
+			continue
+		}
+
+		req := createRequest{
+			fromEmail: sender,
 			messageID: r.FormValue("Message-Id"),
 			subject:   r.FormValue("Subject"),
 			url:       url,
+			tenantID:  tenant.ID,
+		}
+		req.reply = func(message string) { h.Mailgun.SendReply(req, message) }
+
+		if err := h.Queue.Enqueue(r.Context(), req); err != nil {
+			Printf("could not durably enqueue %s: %#v", url, err)
+			http.Error(w, "could not save "+url+" to the radar", http.StatusInternalServerError)
+			return
 		}
+		added++
+	}
+
+	if added == 0 {
+		http.Error(w, "rate limit exceeded, try again later", http.StatusTooManyRequests)
+		return
 	}
 
-	http.Error(w, fmt.Sprintf("added %d urls to today's radar", len(urls)), http.StatusCreated)
+	http.Error(w, fmt.Sprintf("added %d urls to today's radar", added), http.StatusCreated)
 }
@@ -0,0 +1,208 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/go-readability"
+	"golang.org/x/net/html"
+)
+
+// DefaultUserAgent is sent with every enrichment fetch so site operators
+// can identify, and if needed block, radar's crawler.
+const DefaultUserAgent = "radar-bot/1.0 (+https://github.com/parkr/radar)"
+
+// Enricher augments a RadarItem with metadata gathered from its URL
+// before it's persisted. Implementations must never let a slow or broken
+// URL block ingestion: failures should be logged and the item returned
+// otherwise unchanged.
+type Enricher interface {
+	Enrich(ctx context.Context, item RadarItem) RadarItem
+}
+
+// URLEnricher fetches a RadarItem's URL and fills in its title, OpenGraph
+// metadata, a short readability excerpt, and, if enabled, an archive.org
+// snapshot.
+type URLEnricher struct {
+	// HTTPClient is used for every fetch.
+	HTTPClient *http.Client
+
+	// UserAgent sent with every request. NewURLEnricher sets it to
+	// DefaultUserAgent; an empty value is sent as is.
+	UserAgent string
+
+	// Archive, if true, submits the URL to web.archive.org/save/ and
+	// stores the resulting snapshot URL.
+	Archive bool
+}
+
+// NewURLEnricher creates a URLEnricher whose fetches (including the
+// optional archive.org save) are bounded by timeout.
+func NewURLEnricher(timeout time.Duration, archive bool) *URLEnricher {
+	return &URLEnricher{
+		HTTPClient: &http.Client{Timeout: timeout},
+		UserAgent:  DefaultUserAgent,
+		Archive:    archive,
+	}
+}
+
+func (e *URLEnricher) Enrich(ctx context.Context, item RadarItem) RadarItem {
+	body, err := e.fetch(ctx, item.URL)
+	if err != nil {
+		Printf("enrich: could not fetch %s: %#v", item.URL, err)
+		return item
+	}
+
+	meta := parsePageMeta(body)
+	if title := meta.title(); title != "" {
+		item.Title = title
+	}
+	item.Description = meta.ogDescription
+	item.ImageURL = meta.ogImage
+
+	if excerpt, err := extractExcerpt(item.URL, body); err != nil {
+		Printf("enrich: could not extract excerpt for %s: %#v", item.URL, err)
+	} else {
+		item.Excerpt = excerpt
+	}
+
+	if e.Archive {
+		if snapshotURL, err := e.archive(ctx, item.URL); err != nil {
+			Printf("enrich: could not archive %s: %#v", item.URL, err)
+		} else {
+			item.ArchiveURL = snapshotURL
+		}
+	}
+
+	return item
+}
+
+func (e *URLEnricher) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", e.UserAgent)
+
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+}
+
+// archive submits url to the Wayback Machine's "save page now" endpoint
+// and returns the resulting snapshot URL.
+func (e *URLEnricher) archive(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://web.archive.org/save/"+url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", e.UserAgent)
+
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("web.archive.org responded %s for %s", resp.Status, url)
+	}
+
+	if snapshot := resp.Header.Get("Content-Location"); snapshot != "" {
+		return "https://web.archive.org" + snapshot, nil
+	}
+
+	return resp.Request.URL.String(), nil
+}
+
+// pageMeta holds whatever <title> and OpenGraph tags were found on a page.
+type pageMeta struct {
+	titleTag      string
+	ogTitle       string
+	ogDescription string
+	ogImage       string
+}
+
+func (m pageMeta) title() string {
+	if m.ogTitle != "" {
+		return m.ogTitle
+	}
+	return m.titleTag
+}
+
+func parsePageMeta(body []byte) pageMeta {
+	var meta pageMeta
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return meta
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if n.FirstChild != nil {
+					meta.titleTag = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "meta":
+				property := htmlAttr(n, "property")
+				name := htmlAttr(n, "name")
+				content := htmlAttr(n, "content")
+				switch {
+				case property == "og:title":
+					meta.ogTitle = content
+				case property == "og:description", name == "description":
+					meta.ogDescription = content
+				case property == "og:image":
+					meta.ogImage = content
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return meta
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// extractExcerpt runs a readability pass over body and returns a short
+// excerpt suitable for a digest entry. pageURL is passed through to
+// readability so it can resolve the page's relative links and images.
+func extractExcerpt(pageURL string, body []byte) (string, error) {
+	parsedURL, err := neturl.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+
+	article, err := readability.FromReader(strings.NewReader(string(body)), parsedURL)
+	if err != nil {
+		return "", err
+	}
+	return article.Excerpt, nil
+}
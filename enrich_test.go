@@ -0,0 +1,77 @@
+package radar
+
+import "testing"
+
+func TestParsePageMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want pageMeta
+	}{
+		{
+			name: "plain title only",
+			html: `<html><head><title>Plain Title</title></head></html>`,
+			want: pageMeta{titleTag: "Plain Title"},
+		},
+		{
+			name: "opengraph tags",
+			html: `<html><head>
+				<title>Fallback Title</title>
+				<meta property="og:title" content="OG Title">
+				<meta property="og:description" content="An OG description">
+				<meta property="og:image" content="https://example.com/image.png">
+			</head></html>`,
+			want: pageMeta{
+				titleTag:      "Fallback Title",
+				ogTitle:       "OG Title",
+				ogDescription: "An OG description",
+				ogImage:       "https://example.com/image.png",
+			},
+		},
+		{
+			name: "plain meta description when no og:description",
+			html: `<html><head><meta name="description" content="A plain description"></head></html>`,
+			want: pageMeta{ogDescription: "A plain description"},
+		},
+		{
+			name: "empty html yields zero value",
+			html: "",
+			want: pageMeta{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePageMeta([]byte(tt.html))
+			if got != tt.want {
+				t.Fatalf("parsePageMeta() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageMetaTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		meta pageMeta
+		want string
+	}{
+		{name: "prefers og:title", meta: pageMeta{titleTag: "Tag", ogTitle: "OG"}, want: "OG"},
+		{name: "falls back to title tag", meta: pageMeta{titleTag: "Tag"}, want: "Tag"},
+		{name: "empty when neither set", meta: pageMeta{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.meta.title(); got != tt.want {
+				t.Errorf("title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractExcerptRejectsInvalidURL(t *testing.T) {
+	if _, err := extractExcerpt("://not-a-valid-url", []byte("<html></html>")); err == nil {
+		t.Fatal("expected an error for an unparseable page URL, got nil")
+	}
+}
@@ -0,0 +1,88 @@
+package radar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+var labels = []string{"radar"}
+
+var itemsTmpl = template.Must(template.New("items").Parse(`{{range .}}- [ ] [{{.GetTitle}}]({{.URL}}){{if .Excerpt}} — {{.Excerpt}}{{end}}{{if .ArchiveURL}} ([archived]({{.ArchiveURL}})){{end}}
+{{end}}`))
+
+// tmplData holds everything the radar body template needs to render a
+// day's digest: any issues carried over from a previous radar, today's
+// new items, and who to mention in the footer.
+type tmplData struct {
+	OldIssueURL string
+	OldIssues   []RadarItem
+	NewIssues   []RadarItem
+	Mention     string
+}
+
+// generateBody renders data into the Markdown body of a radar issue.
+func generateBody(data *tmplData) (string, error) {
+	if len(data.OldIssues) == 0 && len(data.NewIssues) == 0 {
+		return "Nothing to do today. Nice work! :sparkles:", nil
+	}
+
+	buf := bytes.NewBufferString("A new day! Here's what you have saved:\n\n")
+
+	if data.OldIssueURL != "" {
+		fmt.Fprintf(buf, "[*Previously:*](%s)\n\n", data.OldIssueURL)
+		if err := itemsTmpl.Execute(buf, data.OldIssues); err != nil {
+			return "", err
+		}
+		buf.WriteString("\nNew:\n\n")
+	}
+
+	if err := itemsTmpl.Execute(buf, data.NewIssues); err != nil {
+		return "", err
+	}
+
+	if data.Mention != "" {
+		fmt.Fprintf(buf, "\n/cc %s\n", data.Mention)
+	}
+
+	return buf.String(), nil
+}
+
+func getTitle() string {
+	return fmt.Sprintf("Radar for %s", time.Now().Format("2006-01-02"))
+}
+
+// GenerateRadarIssue assembles tenant's daily digest from its items in
+// radarItemsService and publishes it via tenant.Publisher. If
+// tenant.Finder is non-nil, any still-open links from its previous radar
+// are carried over, and tenant.Mention (if set) is appended as a footer
+// "/cc" line. Reads and writes are scoped to tenant.ID so that one radar
+// deployment can serve several tenants without their items mixing.
+func GenerateRadarIssue(ctx context.Context, radarItemsService RadarItemsService, tenant *Tenant) (string, error) {
+	newIssues, err := radarItemsService.List(ctx, tenant.ID, -1)
+	if err != nil {
+		return "", err
+	}
+
+	data := &tmplData{NewIssues: newIssues, Mention: tenant.Mention}
+
+	if tenant.Finder != nil {
+		issueURL, oldIssues, err := tenant.Finder.FindPreviousRadar(ctx)
+		if err != nil {
+			Printf("error finding previous radar for tenant %s: %#v", tenant.ID, err)
+		} else if issueURL != "" {
+			data.OldIssueURL = issueURL
+			data.OldIssues = oldIssues
+		}
+	}
+
+	body, err := generateBody(data)
+	if err != nil {
+		Printf("couldn't generate a radar body for tenant %s: %#v", tenant.ID, err)
+		return "", err
+	}
+
+	return tenant.Publisher.PublishRadar(ctx, getTitle(), body, labels)
+}
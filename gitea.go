@@ -0,0 +1,118 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// GiteaPublisher publishes radar digests as issues on a Gitea repo and
+// finds the previous day's radar issue by searching for the open "radar"
+// label, the same way GitHubPublisher does.
+type GiteaPublisher struct {
+	client *gitea.Client
+	owner  string
+	name   string
+}
+
+// NewGiteaPublisher creates a GiteaPublisher against the Gitea instance at
+// serverURL, authenticating with token and publishing to repo
+// (e.g. "parkr/radar").
+func NewGiteaPublisher(serverURL, token, repo string) (*GiteaPublisher, error) {
+	repoPieces := strings.SplitN(repo, "/", 2)
+	if len(repoPieces) != 2 {
+		return nil, fmt.Errorf("radar: invalid repo %q, expected owner/name", repo)
+	}
+
+	client, err := gitea.NewClient(serverURL, gitea.SetToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("radar: connecting to gitea at %s: %w", serverURL, err)
+	}
+
+	return &GiteaPublisher{client: client, owner: repoPieces[0], name: repoPieces[1]}, nil
+}
+
+func (p *GiteaPublisher) PublishRadar(ctx context.Context, title, body string, labels []string) (string, error) {
+	issue, _, err := p.client.CreateIssue(p.owner, p.name, gitea.CreateIssueOption{
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(labels) > 0 {
+		if err := p.applyLabels(issue.Index, labels); err != nil {
+			Printf("radar: couldn't apply labels to gitea issue #%d: %#v", issue.Index, err)
+		}
+	}
+
+	return issue.HTMLURL, nil
+}
+
+func (p *GiteaPublisher) applyLabels(index int64, names []string) error {
+	repoLabels, _, err := p.client.ListRepoLabels(p.owner, p.name, gitea.ListLabelsOptions{})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = p.client.AddIssueLabels(p.owner, p.name, index, gitea.IssueLabelsOption{Labels: matchLabelIDs(repoLabels, names)})
+	return err
+}
+
+// matchLabelIDs returns the ID of every label in repoLabels whose Name is
+// in names.
+func matchLabelIDs(repoLabels []*gitea.Label, names []string) []int64 {
+	var ids []int64
+	for _, label := range repoLabels {
+		for _, name := range names {
+			if label.Name == name {
+				ids = append(ids, label.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func (p *GiteaPublisher) FindPreviousRadar(ctx context.Context) (string, []RadarItem, error) {
+	issues, _, err := p.client.ListRepoIssues(p.owner, p.name, gitea.ListIssueOption{
+		State:  gitea.StateOpen,
+		Labels: []string{"radar"},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(issues) == 0 {
+		return "", nil, nil
+	}
+
+	issue := issues[0]
+
+	var items []RadarItem
+	items = append(items, extractLinkedTodosFromMarkdown(issue.Body)...)
+
+	const pageSize = 50
+	err = fetchAllPages(func(page int) (int, error) {
+		comments, _, err := p.client.ListIssueComments(p.owner, p.name, issue.Index, gitea.ListIssueCommentOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, comment := range comments {
+			items = append(items, extractLinkedTodosFromMarkdown(comment.Body)...)
+		}
+		if len(comments) < pageSize {
+			return 0, nil
+		}
+		return page + 1, nil
+	})
+	if err != nil {
+		Printf("radar: error fetching gitea comments: %#v", err)
+		return issue.HTMLURL, items, nil
+	}
+
+	return issue.HTMLURL, items, nil
+}
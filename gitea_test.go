@@ -0,0 +1,40 @@
+package radar
+
+import (
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+func TestMatchLabelIDs(t *testing.T) {
+	repoLabels := []*gitea.Label{
+		{ID: 1, Name: "radar"},
+		{ID: 2, Name: "bug"},
+		{ID: 3, Name: "enhancement"},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []int64
+	}{
+		{name: "single match", names: []string{"radar"}, want: []int64{1}},
+		{name: "multiple matches", names: []string{"bug", "enhancement"}, want: []int64{2, 3}},
+		{name: "no match", names: []string{"wontfix"}, want: nil},
+		{name: "empty names", names: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchLabelIDs(repoLabels, tt.names)
+			if len(got) != len(tt.want) {
+				t.Fatalf("matchLabelIDs() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("matchLabelIDs() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
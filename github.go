@@ -1,13 +1,10 @@
 package radar
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"strings"
-	"text/template"
-	"time"
 
 	"github.com/google/go-github/github"
 	"golang.org/x/oauth2"
@@ -16,45 +13,50 @@ import (
 // Generate and re-use one client per token. Key = token, value = client for token.
 var clients = map[string]*github.Client{}
 
-var labels = []string{"radar"}
-
-var bodyTmpl = template.Must(template.New("body").Parse(`{{range .}}- [ ] [{{.GetTitle}}]({{.URL}})
-{{end}}`))
-
-func GenerateRadarIssue(radarItemsService RadarItemsService, githubToken string, repo string) (*github.Issue, error) {
-	client := getClient(githubToken)
-
-	repoPieces := strings.Split(repo, "/")
-	owner, name := repoPieces[0], repoPieces[1]
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	links, err := radarItemsService.List(ctx, -1)
-	if err != nil {
-		return nil, err
-	}
+// GitHubPublisher publishes radar digests as issues on a GitHub repo and
+// finds the previous day's radar issue by searching for the open "radar"
+// label.
+type GitHubPublisher struct {
+	client *github.Client
+	owner  string
+	name   string
+}
 
-	if issue := getPreviousRadarIssue(ctx, client, owner, name); issue != nil {
-		links = append(links, extractGitHubLinks(ctx, client, owner, name, issue)...)
+// NewGitHubPublisher creates a GitHubPublisher that publishes to repo
+// (e.g. "parkr/radar"), authenticating with githubToken.
+func NewGitHubPublisher(githubToken, repo string) (*GitHubPublisher, error) {
+	repoPieces := strings.SplitN(repo, "/", 2)
+	if len(repoPieces) != 2 {
+		return nil, fmt.Errorf("radar: invalid repo %q, expected owner/name", repo)
 	}
 
-	body, err := joinLinksIntoBody(links)
-	if err != nil {
-		log.Printf("Couldn't get a radar body: %#v", err)
-		return nil, err
-	}
+	return &GitHubPublisher{
+		client: getClient(githubToken),
+		owner:  repoPieces[0],
+		name:   repoPieces[1],
+	}, nil
+}
 
-	newIssue, _, err := client.Issues.Create(ctx, owner, name, &github.IssueRequest{
-		Title:  github.String(getTitle()),
+func (p *GitHubPublisher) PublishRadar(ctx context.Context, title, body string, labels []string) (string, error) {
+	issue, _, err := p.client.Issues.Create(ctx, p.owner, p.name, &github.IssueRequest{
+		Title:  github.String(title),
 		Body:   github.String(body),
 		Labels: &labels,
 	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return newIssue, nil
+	return issue.GetHTMLURL(), nil
+}
+
+func (p *GitHubPublisher) FindPreviousRadar(ctx context.Context) (string, []RadarItem, error) {
+	issue := getPreviousRadarIssue(ctx, p.client, p.owner, p.name)
+	if issue == nil {
+		return "", nil, nil
+	}
+
+	return issue.GetHTMLURL(), extractGitHubLinks(ctx, p.client, p.owner, p.name, issue), nil
 }
 
 func getPreviousRadarIssue(ctx context.Context, client *github.Client, owner, name string) *github.Issue {
@@ -78,20 +80,6 @@ func getPreviousRadarIssue(ctx context.Context, client *github.Client, owner, na
 	return &result.Issues[0]
 }
 
-func getTitle() string {
-	return fmt.Sprintf("Radar for %s", time.Now().Format("2006-01-02"))
-}
-
-func joinLinksIntoBody(links []RadarItem) (string, error) {
-	if len(links) == 0 {
-		return "Nothing to do today. Nice work! :sparkles:", nil
-	}
-
-	buf := bytes.NewBufferString("A new day! Here's what you have saved:\n\n")
-	err := bodyTmpl.Execute(buf, links)
-	return buf.String(), err
-}
-
 func extractGitHubLinks(ctx context.Context, client *github.Client, owner, name string, issue *github.Issue) []RadarItem {
 	var items []RadarItem
 
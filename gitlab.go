@@ -0,0 +1,85 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// GitLabPublisher publishes radar digests as issues on a GitLab project
+// and finds the previous day's radar issue by searching for the open
+// "radar" label, the same way GitHubPublisher does.
+type GitLabPublisher struct {
+	client  *gitlab.Client
+	project string
+}
+
+// NewGitLabPublisher creates a GitLabPublisher against the GitLab
+// instance at baseURL (empty for gitlab.com), authenticating with token
+// and publishing to project (e.g. "parkr/radar").
+func NewGitLabPublisher(baseURL, token, project string) (*GitLabPublisher, error) {
+	var opts []gitlab.ClientOptionFunc
+	if baseURL != "" {
+		opts = append(opts, gitlab.WithBaseURL(baseURL))
+	}
+
+	client, err := gitlab.NewClient(token, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("radar: connecting to gitlab: %w", err)
+	}
+
+	return &GitLabPublisher{client: client, project: project}, nil
+}
+
+func (p *GitLabPublisher) PublishRadar(ctx context.Context, title, body string, labels []string) (string, error) {
+	issue, _, err := p.client.Issues.CreateIssue(p.project, &gitlab.CreateIssueOptions{
+		Title:       gitlab.String(title),
+		Description: gitlab.String(body),
+		Labels:      gitlab.Labels(labels),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return issue.WebURL, nil
+}
+
+func (p *GitLabPublisher) FindPreviousRadar(ctx context.Context) (string, []RadarItem, error) {
+	opened := "opened"
+	issues, _, err := p.client.Issues.ListProjectIssues(p.project, &gitlab.ListProjectIssuesOptions{
+		State:  &opened,
+		Labels: gitlab.Labels{"radar"},
+		Sort:   gitlab.String("desc"),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(issues) == 0 {
+		return "", nil, nil
+	}
+
+	issue := issues[0]
+
+	var items []RadarItem
+	items = append(items, extractLinkedTodosFromMarkdown(issue.Description)...)
+
+	err = fetchAllPages(func(page int) (int, error) {
+		notes, resp, err := p.client.Notes.ListIssueNotes(p.project, issue.IID, &gitlab.ListIssueNotesOptions{
+			ListOptions: gitlab.ListOptions{PerPage: 100, Page: page},
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, note := range notes {
+			items = append(items, extractLinkedTodosFromMarkdown(note.Body)...)
+		}
+		return resp.NextPage, nil
+	})
+	if err != nil {
+		Printf("radar: error fetching gitlab notes: %#v", err)
+		return issue.WebURL, items, nil
+	}
+
+	return issue.WebURL, items, nil
+}
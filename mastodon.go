@@ -0,0 +1,143 @@
+package radar
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mvdan.cc/xurls/v2"
+)
+
+// NewMastodonHandler creates a MastodonHandler that enqueues onto queue.
+// sharedSecret is the bearer token the bridge must present in its
+// Authorization header on every webhook delivery. tenants must have a
+// Tenant registered under DefaultTenantID, since mention notifications
+// carry no sender address to resolve one from.
+func NewMastodonHandler(queue *Queue, sharedSecret string, tenants *TenantDirectory, debug bool) MastodonHandler {
+	return MastodonHandler{Queue: queue, SharedSecret: sharedSecret, Tenants: tenants, Debug: debug}
+}
+
+// MastodonHandler accepts webhook deliveries describing Mastodon mention
+// notifications, as emitted by a bridge subscribed to the streaming
+// API's "notification" event, and enqueues any URLs found in the
+// mentioning status.
+type MastodonHandler struct {
+	// The shared ingestion queue.
+	Queue *Queue
+
+	// SharedSecret is the bearer token the bridge must present as
+	// "Authorization: Bearer <SharedSecret>" on every delivery.
+	SharedSecret string
+
+	// Tenants resolves the DefaultTenantID tenant and enforces its rate
+	// limit, since mention notifications carry no sender address of
+	// their own.
+	Tenants *TenantDirectory
+
+	// Enable debug logging.
+	Debug bool
+}
+
+type mastodonNotification struct {
+	Notification struct {
+		Type   string `json:"type"`
+		Status struct {
+			Content string `json:"content"`
+			URL     string `json:"url"`
+			Account struct {
+				Acct string `json:"acct"`
+			} `json:"account"`
+		} `json:"status"`
+	} `json:"notification"`
+}
+
+func (h MastodonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.isValidBearerToken(r) {
+		Println("invalid or missing mastodon webhook bearer token")
+		http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	var payload mastodonNotification
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		Println("could not decode mastodon webhook:", err)
+		http.Error(w, "could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	if payload.Notification.Type != "mention" {
+		if h.Debug {
+			Printf("ignoring mastodon notification of type %q", payload.Notification.Type)
+		}
+		http.Error(w, "ignored notification type "+payload.Notification.Type, http.StatusOK)
+		return
+	}
+
+	content := payload.Notification.Status.Content
+	if h.Debug {
+		Printf("mastodon mention content: %#v", content)
+	}
+
+	var urls []string
+	if matches := xurls.Strict().FindAllString(content, -1); matches != nil && len(matches) > 0 {
+		urls = append(urls, matches...)
+	}
+
+	if len(urls) == 0 {
+		Println("no urls in mastodon mention: ", content)
+		http.Error(w, "no urls present in mention", http.StatusOK)
+		return
+	}
+
+	tenant, ok := h.Tenants.LookupDefault()
+	if !ok {
+		Println("no default tenant configured for mastodon submissions")
+		http.Error(w, "radar is not configured to accept mastodon submissions", http.StatusInternalServerError)
+		return
+	}
+
+	added := 0
+	for _, url := range urls {
+		if !h.Tenants.Allow(tenant.ID) {
+			Printf("rate limit exceeded for tenant %s, dropping %s", tenant.ID, url)
+			continue
+		}
+
+		req := createRequest{
+			fromEmail: payload.Notification.Status.Account.Acct,
+			subject:   "Mastodon mention",
+			url:       url,
+			tenantID:  tenant.ID,
+			reply:     func(message string) { Printf("mastodon submission result: %s", message) },
+		}
+
+		if err := h.Queue.Enqueue(r.Context(), req); err != nil {
+			Printf("could not durably enqueue %s: %#v", url, err)
+			http.Error(w, "could not save "+url+" to the radar", http.StatusInternalServerError)
+			return
+		}
+		added++
+	}
+
+	http.Error(w, fmt.Sprintf("added %d urls to today's radar", added), http.StatusCreated)
+}
+
+// isValidBearerToken checks the request's Authorization header against
+// h.SharedSecret, the bridge's shared secret. Without this check anyone
+// who can reach the endpoint could forge mention notifications and get
+// arbitrary URLs durably enqueued.
+func (h MastodonHandler) isValidBearerToken(r *http.Request) bool {
+	if h.SharedSecret == "" {
+		return false
+	}
+
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(strings.TrimPrefix(auth, prefix)), []byte(h.SharedSecret))
+}
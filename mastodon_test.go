@@ -0,0 +1,36 @@
+package radar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMastodonHandlerIsValidBearerToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		authority string
+		want      bool
+	}{
+		{name: "valid token", secret: "s3cr3t", authority: "Bearer s3cr3t", want: true},
+		{name: "wrong token", secret: "s3cr3t", authority: "Bearer wrong", want: false},
+		{name: "missing header", secret: "s3cr3t", authority: "", want: false},
+		{name: "missing Bearer prefix", secret: "s3cr3t", authority: "s3cr3t", want: false},
+		{name: "no secret configured", secret: "", authority: "Bearer s3cr3t", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mastodon", nil)
+			if tt.authority != "" {
+				r.Header.Set("Authorization", tt.authority)
+			}
+
+			h := MastodonHandler{SharedSecret: tt.secret}
+			if got := h.isValidBearerToken(r); got != tt.want {
+				t.Errorf("isValidBearerToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
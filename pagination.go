@@ -0,0 +1,18 @@
+package radar
+
+// fetchAllPages drives repeated paginated API fetches. fetch is called
+// with the page to request; it's responsible for its own side effects
+// (typically appending results onto a caller-owned accumulator) and
+// returns the next page to request, or 0 once there's nothing left.
+// fetchAllPages stops on the first error fetch returns, or once fetch
+// reports page 0.
+func fetchAllPages(fetch func(page int) (nextPage int, err error)) error {
+	for page := 1; page != 0; {
+		next, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		page = next
+	}
+	return nil
+}
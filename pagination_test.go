@@ -0,0 +1,65 @@
+package radar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFetchAllPages(t *testing.T) {
+	t.Run("walks every page until the last one reports done", func(t *testing.T) {
+		var seen []int
+		pageCounts := map[int]int{1: 3, 2: 3, 3: 1} // page 3 is short: stop after it
+
+		err := fetchAllPages(func(page int) (int, error) {
+			seen = append(seen, page)
+			if pageCounts[page] == 3 {
+				return page + 1, nil
+			}
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("fetchAllPages: %#v", err)
+		}
+		want := []int{1, 2, 3}
+		if len(seen) != len(want) {
+			t.Fatalf("fetched pages %v, want %v", seen, want)
+		}
+		for i := range want {
+			if seen[i] != want[i] {
+				t.Fatalf("fetched pages %v, want %v", seen, want)
+			}
+		}
+	})
+
+	t.Run("stops immediately when the first page is already short", func(t *testing.T) {
+		calls := 0
+		err := fetchAllPages(func(page int) (int, error) {
+			calls++
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("fetchAllPages: %#v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected exactly 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("stops and surfaces the error on a failed page", func(t *testing.T) {
+		wantErr := errors.New("boom")
+		calls := 0
+		err := fetchAllPages(func(page int) (int, error) {
+			calls++
+			if page == 2 {
+				return 0, wantErr
+			}
+			return page + 1, nil
+		})
+		if err != wantErr {
+			t.Fatalf("fetchAllPages: got %#v, want %#v", err, wantErr)
+		}
+		if calls != 2 {
+			t.Fatalf("expected exactly 2 calls before erroring, got %d", calls)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package radar
+
+import "context"
+
+// Publisher publishes a day's radar digest to a forge (GitHub, Gitea,
+// GitLab, ...) and returns the URL of whatever it created there.
+type Publisher interface {
+	PublishRadar(ctx context.Context, title, body string, labels []string) (issueURL string, err error)
+}
+
+// PreviousRadarFinder locates the most recently opened still-open radar
+// issue, if any, so its still-outstanding links can be carried over into
+// today's digest.
+type PreviousRadarFinder interface {
+	FindPreviousRadar(ctx context.Context) (issueURL string, links []RadarItem, err error)
+}
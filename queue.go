@@ -0,0 +1,218 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxDeliveryAttempts bounds how many times a request is redelivered
+// after a transient storage error before it's moved to the backend's
+// dead-letter queue.
+const maxDeliveryAttempts = 5
+
+// Backend durably persists createRequests so an unexpected shutdown (or a
+// burst of submissions while storage is slow) doesn't silently drop one.
+// BoltBackend is the default, disk-backed driver; RedisBackend and
+// NATSBackend are optional. Each is created by its own constructor
+// (OpenBoltBackend, OpenRedisBackend, OpenNATSBackend); there is no
+// DSN-based selection like storage.Open's.
+type Backend interface {
+	// Enqueue durably persists req and returns only once it's safe from
+	// a crash. A Submitter should only acknowledge 201 to its caller
+	// after this returns nil.
+	Enqueue(ctx context.Context, req createRequest) (deliveryID string, err error)
+
+	// Dequeue blocks until a request is available, returning it along
+	// with an opaque delivery id used to Ack, Nack, or DeadLetter it.
+	Dequeue(ctx context.Context) (req createRequest, deliveryID string, err error)
+
+	// Ack marks deliveryID as processed and safe to discard.
+	Ack(ctx context.Context, deliveryID string) error
+
+	// Nack returns deliveryID to the queue for redelivery.
+	Nack(ctx context.Context, deliveryID string) error
+
+	// DeadLetter moves deliveryID to the backend's dead-letter queue once
+	// its retry budget is exhausted.
+	DeadLetter(ctx context.Context, deliveryID string) error
+
+	// Close shuts down the backend's underlying connection/storage.
+	Close() error
+}
+
+// Queue is the shared ingestion pipeline that every Submitter feeds into.
+// It durably enqueues onto a Backend, then a single worker dequeues,
+// persists each request via RadarItemsStorageService, and acks or nacks
+// it so the backend accurately reflects what has and hasn't been
+// persisted.
+type Queue struct {
+	backend  Backend
+	items    RadarItemsStorageService
+	enricher Enricher
+
+	mu       sync.Mutex
+	replyFor map[string]func(string) // deliveryID -> reply, for in-process requests
+	attempts map[string]int          // deliveryID -> delivery attempts so far
+}
+
+// NewQueue creates a Queue backed by backend for durability and items for
+// persistence. enricher may be nil, in which case items are stored
+// exactly as submitted.
+func NewQueue(backend Backend, items RadarItemsStorageService, enricher Enricher) *Queue {
+	return &Queue{
+		backend:  backend,
+		items:    items,
+		enricher: enricher,
+		replyFor: make(map[string]func(string)),
+		attempts: make(map[string]int),
+	}
+}
+
+// Enqueue durably persists req via the backend, blocking until it's
+// safely stored, and remembers req.reply so Start can call it once the
+// request is processed. Callers should treat a non-nil error as "this
+// request was not saved" and must not acknowledge their caller.
+func (q *Queue) Enqueue(ctx context.Context, req createRequest) error {
+	deliveryID, err := q.backend.Enqueue(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if req.reply != nil {
+		q.mu.Lock()
+		q.replyFor[deliveryID] = req.reply
+		q.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Start dequeues requests one at a time, persists each via
+// RadarItemsStorageService, and acks on success or nacks (with bounded
+// retries, then a move to the dead-letter queue) on failure. It runs
+// until ctx is cancelled and is meant to be run in its own goroutine.
+func (q *Queue) Start(ctx context.Context) {
+	for {
+		req, deliveryID, err := q.backend.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			Printf("error dequeuing request: %#v", err)
+			continue
+		}
+
+		reply := q.takeReply(deliveryID, req.url)
+
+		item := RadarItem{URL: req.url}
+		if q.enricher != nil {
+			item = q.enricher.Enrich(ctx, item)
+		}
+
+		createCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err = q.items.Create(createCtx, req.tenantID, item)
+		cancel()
+
+		if err != nil {
+			Printf("error saving '%s': %#v", req.url, err)
+			q.nackOrDeadLetter(ctx, deliveryID)
+			reply("Could not save " + req.url + " to the radar: " + err.Error())
+			continue
+		}
+
+		if ackErr := q.backend.Ack(ctx, deliveryID); ackErr != nil {
+			Printf("error acking delivery %s: %#v", deliveryID, ackErr)
+		}
+		q.clearAttempts(deliveryID)
+		reply("Added " + req.url + " to the radar.")
+		Printf("saved url=%s to database", req.url)
+	}
+}
+
+func (q *Queue) nackOrDeadLetter(ctx context.Context, deliveryID string) {
+	q.mu.Lock()
+	q.attempts[deliveryID]++
+	attempts := q.attempts[deliveryID]
+	q.mu.Unlock()
+
+	if attempts >= maxDeliveryAttempts {
+		Printf("giving up on delivery %s after %d attempts, dead-lettering", deliveryID, attempts)
+		if err := q.backend.DeadLetter(ctx, deliveryID); err != nil {
+			Printf("error dead-lettering delivery %s: %#v", deliveryID, err)
+		}
+		q.clearAttempts(deliveryID)
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(nackBackoff(attempts)):
+	}
+
+	if err := q.backend.Nack(ctx, deliveryID); err != nil {
+		Printf("error nacking delivery %s: %#v", deliveryID, err)
+	}
+}
+
+// nackBackoff returns how long Start should wait before redelivering a
+// request that has failed attempts times. Without this, a backend whose
+// Nack is a no-op (e.g. BoltBackend, which just leaves the item at the
+// head of the queue) gets redelivered with zero delay, burning through
+// maxDeliveryAttempts and dead-lettering within milliseconds of any
+// transient storage hiccup instead of giving it a chance to clear.
+func nackBackoff(attempts int) time.Duration {
+	d := time.Duration(attempts) * time.Second
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
+
+func (q *Queue) clearAttempts(deliveryID string) {
+	q.mu.Lock()
+	delete(q.attempts, deliveryID)
+	q.mu.Unlock()
+}
+
+// takeReply returns the reply closure registered for deliveryID at
+// Enqueue time, if this process is still the one that submitted it.
+// Requests drained from the backend after a crash (or migrated in via
+// DrainLegacyChannel) have no live closure, since whoever submitted them
+// is long gone, so their outcome is only logged.
+func (q *Queue) takeReply(deliveryID, url string) func(string) {
+	q.mu.Lock()
+	reply, ok := q.replyFor[deliveryID]
+	delete(q.replyFor, deliveryID)
+	q.mu.Unlock()
+
+	if ok {
+		return reply
+	}
+	return func(message string) {
+		Printf("no live submitter for delivery %s (url=%s): %s", deliveryID, url, message)
+	}
+}
+
+// Shutdown closes the backend and shuts down the underlying storage.
+func (q *Queue) Shutdown(ctx context.Context) {
+	if err := q.backend.Close(); err != nil {
+		Printf("error closing queue backend: %#v", err)
+	}
+	q.items.Shutdown(ctx)
+}
+
+// DrainLegacyChannel copies any createRequests still sitting in a legacy
+// in-memory channel (as used before durable queueing) into backend, so a
+// rolling upgrade doesn't drop whatever was buffered at cutover. It
+// returns once legacy is closed and fully drained.
+func DrainLegacyChannel(ctx context.Context, legacy <-chan createRequest, backend Backend) error {
+	for req := range legacy {
+		if _, err := backend.Enqueue(ctx, req); err != nil {
+			return fmt.Errorf("queue: draining legacy channel: %w", err)
+		}
+	}
+	return nil
+}
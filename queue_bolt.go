@@ -0,0 +1,190 @@
+package radar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltQueueBucket      = []byte("queue")
+	boltDeadLetterBucket = []byte("dead_letter")
+)
+
+// BoltBackend is the default Backend: createRequests are written to a
+// BoltDB file on disk before Enqueue returns, so a crash between enqueue
+// and processing never silently drops a submission.
+type BoltBackend struct {
+	db *bolt.DB
+}
+
+// OpenBoltBackend opens (creating if necessary) a BoltDB file at path.
+func OpenBoltBackend(path string) (*BoltBackend, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("queue: opening bolt db at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltQueueBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltDeadLetterBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("queue: initializing bolt db: %w", err)
+	}
+
+	return &BoltBackend{db: db}, nil
+}
+
+// queuedRequest is the on-disk, serializable form of a createRequest.
+type queuedRequest struct {
+	FromEmail string
+	MessageID string
+	Subject   string
+	URL       string
+	TenantID  string
+}
+
+func toQueuedRequest(req createRequest) queuedRequest {
+	return queuedRequest{
+		FromEmail: req.fromEmail,
+		MessageID: req.messageID,
+		Subject:   req.subject,
+		URL:       req.url,
+		TenantID:  req.tenantID,
+	}
+}
+
+func (q queuedRequest) toCreateRequest() createRequest {
+	return createRequest{
+		fromEmail: q.FromEmail,
+		messageID: q.MessageID,
+		subject:   q.Subject,
+		url:       q.URL,
+		tenantID:  q.TenantID,
+	}
+}
+
+func (b *BoltBackend) Enqueue(ctx context.Context, req createRequest) (string, error) {
+	var deliveryID string
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltQueueBucket)
+
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		// Zero-padded so the key's byte order matches its numeric
+		// order: Dequeue relies on Cursor().First() returning the
+		// lexicographically smallest key as the oldest request.
+		deliveryID = fmt.Sprintf("%020d", id)
+
+		data, err := json.Marshal(toQueuedRequest(req))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(deliveryID), data)
+	})
+
+	return deliveryID, err
+}
+
+// Dequeue returns the oldest request still in the queue bucket, polling
+// until one is available or ctx is cancelled. A bucket entry that fails
+// to unmarshal is moved straight to the dead-letter bucket: leaving it in
+// place would make Cursor().First() return the same corrupt entry
+// forever, starving every request queued behind it.
+func (b *BoltBackend) Dequeue(ctx context.Context) (createRequest, string, error) {
+	for {
+		var (
+			deliveryID string
+			queued     queuedRequest
+			found      bool
+		)
+
+		err := b.db.Update(func(tx *bolt.Tx) error {
+			queue := tx.Bucket(boltQueueBucket)
+
+			for {
+				k, v := queue.Cursor().First()
+				if k == nil {
+					return nil
+				}
+				// Cursor-returned slices are only valid until the next
+				// write in this transaction, so copy them before any
+				// Put/Delete below.
+				key := append([]byte(nil), k...)
+				val := append([]byte(nil), v...)
+
+				if err := json.Unmarshal(val, &queued); err != nil {
+					Printf("queue: dropping corrupt bolt entry %s: %#v", string(key), err)
+					if err := tx.Bucket(boltDeadLetterBucket).Put(key, val); err != nil {
+						return err
+					}
+					if err := queue.Delete(key); err != nil {
+						return err
+					}
+					continue
+				}
+
+				found = true
+				deliveryID = string(key)
+				return nil
+			}
+		})
+		if err != nil {
+			return createRequest{}, "", err
+		}
+
+		if found {
+			return queued.toCreateRequest(), deliveryID, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return createRequest{}, "", ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func (b *BoltBackend) Ack(ctx context.Context, deliveryID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltQueueBucket).Delete([]byte(deliveryID))
+	})
+}
+
+// Nack leaves deliveryID in place; the next Dequeue hands it out again.
+func (b *BoltBackend) Nack(ctx context.Context, deliveryID string) error {
+	return nil
+}
+
+func (b *BoltBackend) DeadLetter(ctx context.Context, deliveryID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		queue := tx.Bucket(boltQueueBucket)
+
+		data := queue.Get([]byte(deliveryID))
+		if data == nil {
+			return nil
+		}
+
+		if err := tx.Bucket(boltDeadLetterBucket).Put([]byte(deliveryID), data); err != nil {
+			return err
+		}
+
+		return queue.Delete([]byte(deliveryID))
+	})
+}
+
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
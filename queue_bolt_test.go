@@ -0,0 +1,125 @@
+package radar
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestBoltBackend(t *testing.T) *BoltBackend {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "queue.db")
+	b, err := OpenBoltBackend(path)
+	if err != nil {
+		t.Fatalf("OpenBoltBackend: %#v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+// TestBoltBackendFIFOOrderPastDoubleDigits enqueues more than 10 requests
+// so the sequence crosses into double digits, and checks that Dequeue
+// still returns them in insertion order. Before the delivery id was
+// zero-padded, unpadded decimal keys like "10" sorted before "9" and
+// broke FIFO order at exactly this point.
+func TestBoltBackendFIFOOrderPastDoubleDigits(t *testing.T) {
+	b := openTestBoltBackend(t)
+	ctx := context.Background()
+
+	const n = 12
+	for i := 0; i < n; i++ {
+		if _, err := b.Enqueue(ctx, createRequest{url: fmt.Sprintf("https://example.com/%d", i)}); err != nil {
+			t.Fatalf("Enqueue(%d): %#v", i, err)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		req, deliveryID, err := b.Dequeue(ctx)
+		if err != nil {
+			t.Fatalf("Dequeue(%d): %#v", i, err)
+		}
+		want := fmt.Sprintf("https://example.com/%d", i)
+		if req.url != want {
+			t.Fatalf("Dequeue(%d) = %q, want %q (out of FIFO order)", i, req.url, want)
+		}
+		if err := b.Ack(ctx, deliveryID); err != nil {
+			t.Fatalf("Ack(%d): %#v", i, err)
+		}
+	}
+}
+
+// TestBoltBackendNackRedelivers checks that a Nacked request is handed
+// back out by the next Dequeue, since BoltBackend.Nack leaves it in
+// place for redelivery rather than requeueing it elsewhere.
+func TestBoltBackendNackRedelivers(t *testing.T) {
+	b := openTestBoltBackend(t)
+	ctx := context.Background()
+
+	if _, err := b.Enqueue(ctx, createRequest{url: "https://example.com/retry-me"}); err != nil {
+		t.Fatalf("Enqueue: %#v", err)
+	}
+
+	req, deliveryID, err := b.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue: %#v", err)
+	}
+	if req.url != "https://example.com/retry-me" {
+		t.Fatalf("Dequeue = %q, want https://example.com/retry-me", req.url)
+	}
+
+	if err := b.Nack(ctx, deliveryID); err != nil {
+		t.Fatalf("Nack: %#v", err)
+	}
+
+	req, redeliveredID, err := b.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue after nack: %#v", err)
+	}
+	if req.url != "https://example.com/retry-me" {
+		t.Fatalf("Dequeue after nack = %q, want https://example.com/retry-me", req.url)
+	}
+	if redeliveredID != deliveryID {
+		t.Fatalf("redelivered id %q, want original id %q", redeliveredID, deliveryID)
+	}
+}
+
+// TestBoltBackendDeadLetterStopsRedelivery checks that DeadLetter removes
+// the request from the queue bucket (so it's never redelivered) and
+// parks it in the dead-letter bucket instead.
+func TestBoltBackendDeadLetterStopsRedelivery(t *testing.T) {
+	b := openTestBoltBackend(t)
+	ctx := context.Background()
+
+	if _, err := b.Enqueue(ctx, createRequest{url: "https://example.com/give-up"}); err != nil {
+		t.Fatalf("Enqueue: %#v", err)
+	}
+
+	_, deliveryID, err := b.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue: %#v", err)
+	}
+
+	if err := b.DeadLetter(ctx, deliveryID); err != nil {
+		t.Fatalf("DeadLetter: %#v", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := b.Dequeue(timeoutCtx); err == nil {
+		t.Fatal("expected Dequeue to block on an empty queue after dead-lettering, but it returned a request")
+	}
+
+	b.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(boltQueueBucket).Get([]byte(deliveryID)); v != nil {
+			t.Fatal("dead-lettered entry is still present in the queue bucket")
+		}
+		if v := tx.Bucket(boltDeadLetterBucket).Get([]byte(deliveryID)); v == nil {
+			t.Fatal("dead-lettered entry is missing from the dead-letter bucket")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,142 @@
+package radar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	natsStreamName    = "RADAR_QUEUE"
+	natsSubject       = "radar.queue"
+	natsDeadLetterSub = "radar.queue.dead-letter"
+	natsDurableName   = "radar-worker"
+)
+
+// NATSBackend durably persists createRequests in a NATS JetStream stream.
+// Dequeue uses a durable pull consumer so redelivery survives both a
+// client restart and a server restart.
+type NATSBackend struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+	sub  *nats.Subscription
+
+	// pending tracks in-flight messages by delivery id so Ack/Nack/
+	// DeadLetter can find the *nats.Msg to acknowledge.
+	pending sync.Map
+}
+
+// OpenNATSBackend connects to the NATS server at url and ensures the
+// backing JetStream stream and durable consumer exist.
+func OpenNATSBackend(url string) (*NATSBackend, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("queue: connecting to nats at %s: %w", url, err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("queue: getting jetstream context: %w", err)
+	}
+
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:     natsStreamName,
+		Subjects: []string{natsSubject, natsDeadLetterSub},
+	}); err != nil && err != nats.ErrStreamNameAlreadyInUse {
+		conn.Close()
+		return nil, fmt.Errorf("queue: creating stream: %w", err)
+	}
+
+	sub, err := js.PullSubscribe(natsSubject, natsDurableName)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("queue: creating durable consumer: %w", err)
+	}
+
+	return &NATSBackend{conn: conn, js: js, sub: sub}, nil
+}
+
+func (n *NATSBackend) Enqueue(ctx context.Context, req createRequest) (string, error) {
+	data, err := json.Marshal(toQueuedRequest(req))
+	if err != nil {
+		return "", err
+	}
+
+	ack, err := n.js.Publish(natsSubject, data)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(ack.Sequence, 10), nil
+}
+
+func (n *NATSBackend) Dequeue(ctx context.Context) (createRequest, string, error) {
+	msgs, err := n.sub.Fetch(1, nats.Context(ctx))
+	if err != nil {
+		return createRequest{}, "", err
+	}
+
+	msg := msgs[0]
+
+	var queued queuedRequest
+	if err := json.Unmarshal(msg.Data, &queued); err != nil {
+		_ = msg.Nak()
+		return createRequest{}, "", err
+	}
+
+	meta, err := msg.Metadata()
+	if err != nil {
+		_ = msg.Nak()
+		return createRequest{}, "", err
+	}
+
+	n.pending.Store(deliveryIDFor(meta), msg)
+
+	return queued.toCreateRequest(), deliveryIDFor(meta), nil
+}
+
+func (n *NATSBackend) Ack(ctx context.Context, deliveryID string) error {
+	msg, ok := n.pending.LoadAndDelete(deliveryID)
+	if !ok {
+		return nil
+	}
+	return msg.(*nats.Msg).Ack()
+}
+
+func (n *NATSBackend) Nack(ctx context.Context, deliveryID string) error {
+	msg, ok := n.pending.LoadAndDelete(deliveryID)
+	if !ok {
+		return nil
+	}
+	return msg.(*nats.Msg).Nak()
+}
+
+// DeadLetter republishes the message onto the dead-letter subject and
+// acks the original so JetStream stops redelivering it.
+func (n *NATSBackend) DeadLetter(ctx context.Context, deliveryID string) error {
+	msg, ok := n.pending.LoadAndDelete(deliveryID)
+	if !ok {
+		return nil
+	}
+
+	natsMsg := msg.(*nats.Msg)
+	if _, err := n.js.Publish(natsDeadLetterSub, natsMsg.Data); err != nil {
+		return err
+	}
+
+	return natsMsg.Ack()
+}
+
+func (n *NATSBackend) Close() error {
+	n.conn.Close()
+	return nil
+}
+
+func deliveryIDFor(meta *nats.MsgMetadata) string {
+	return strconv.FormatUint(meta.Sequence.Stream, 10)
+}
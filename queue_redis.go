@@ -0,0 +1,101 @@
+package radar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	redisQueueKey      = "radar:queue"
+	redisProcessingKey = "radar:queue:processing"
+	redisDeadLetterKey = "radar:queue:dead-letter"
+	redisNextIDKey     = "radar:queue:next_id"
+)
+
+// redisEnvelope wraps a queuedRequest with a monotonic id from
+// redisNextIDKey, so that two submissions with byte-identical content
+// still serialize to distinct list entries.
+type redisEnvelope struct {
+	ID      string        `json:"id"`
+	Request queuedRequest `json:"request"`
+}
+
+// RedisBackend durably persists createRequests in a Redis list. Dequeue
+// uses BRPOPLPUSH to atomically move an item onto a processing list, so a
+// crash between Dequeue and Ack/Nack/DeadLetter never loses it.
+type RedisBackend struct {
+	client *redis.Client
+}
+
+// OpenRedisBackend connects to the Redis instance at addr.
+func OpenRedisBackend(addr string) (*RedisBackend, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("queue: connecting to redis at %s: %w", addr, err)
+	}
+	return &RedisBackend{client: client}, nil
+}
+
+// Enqueue pushes req onto the queue list, tagged with a fresh id from
+// redisNextIDKey. The envelope's JSON encoding doubles as this request's
+// delivery id, since Redis list entries aren't otherwise addressable;
+// the embedded id keeps that encoding unique even when two requests
+// carry identical content.
+func (r *RedisBackend) Enqueue(ctx context.Context, req createRequest) (string, error) {
+	id, err := r.client.Incr(ctx, redisNextIDKey).Result()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(redisEnvelope{ID: strconv.FormatInt(id, 10), Request: toQueuedRequest(req)})
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.client.LPush(ctx, redisQueueKey, data).Err(); err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (r *RedisBackend) Dequeue(ctx context.Context) (createRequest, string, error) {
+	deliveryID, err := r.client.BRPopLPush(ctx, redisQueueKey, redisProcessingKey, 0).Result()
+	if err != nil {
+		return createRequest{}, "", err
+	}
+
+	var envelope redisEnvelope
+	if err := json.Unmarshal([]byte(deliveryID), &envelope); err != nil {
+		return createRequest{}, "", err
+	}
+
+	return envelope.Request.toCreateRequest(), deliveryID, nil
+}
+
+func (r *RedisBackend) Ack(ctx context.Context, deliveryID string) error {
+	return r.client.LRem(ctx, redisProcessingKey, 1, deliveryID).Err()
+}
+
+// Nack moves deliveryID from the processing list back onto the queue.
+func (r *RedisBackend) Nack(ctx context.Context, deliveryID string) error {
+	if err := r.client.LRem(ctx, redisProcessingKey, 1, deliveryID).Err(); err != nil {
+		return err
+	}
+	return r.client.LPush(ctx, redisQueueKey, deliveryID).Err()
+}
+
+func (r *RedisBackend) DeadLetter(ctx context.Context, deliveryID string) error {
+	if err := r.client.LRem(ctx, redisProcessingKey, 1, deliveryID).Err(); err != nil {
+		return err
+	}
+	return r.client.LPush(ctx, redisDeadLetterKey, deliveryID).Err()
+}
+
+func (r *RedisBackend) Close() error {
+	return r.client.Close()
+}
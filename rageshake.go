@@ -0,0 +1,111 @@
+package radar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mvdan.cc/xurls/v2"
+)
+
+// NewRageshakeHandler creates a RageshakeHandler that enqueues onto queue.
+// tenants must have a Tenant registered under DefaultTenantID, since
+// rageshake reports carry no sender address to resolve one from.
+func NewRageshakeHandler(queue *Queue, tenants *TenantDirectory, debug bool) RageshakeHandler {
+	return RageshakeHandler{Queue: queue, Tenants: tenants, Debug: debug}
+}
+
+// RageshakeHandler accepts Matrix-style rageshake bug report submissions
+// (https://github.com/matrix-org/rageshake) and enqueues any URLs found
+// in the free-text field. Rageshake posts either a JSON body or a
+// multipart form, both carrying at least "text" and "user_agent".
+type RageshakeHandler struct {
+	// The shared ingestion queue.
+	Queue *Queue
+
+	// Tenants resolves the DefaultTenantID tenant and enforces its rate
+	// limit, since rageshake reports carry no sender address of their own.
+	Tenants *TenantDirectory
+
+	// Enable debug logging.
+	Debug bool
+}
+
+type rageshakeReport struct {
+	Text      string `json:"text"`
+	UserAgent string `json:"user_agent"`
+}
+
+func (h RageshakeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var report rageshakeReport
+
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			Println("could not decode rageshake report:", err)
+			http.Error(w, "could not decode request body", http.StatusBadRequest)
+			return
+		}
+	case strings.HasPrefix(contentType, "multipart/"):
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			Println("could not parse rageshake multipart form:", err)
+			http.Error(w, "could not parse request body", http.StatusBadRequest)
+			return
+		}
+		report.Text = r.FormValue("text")
+		report.UserAgent = r.FormValue("user_agent")
+	default:
+		Println("don't know how to handle Content-Type:", contentType)
+		http.Error(w, "cannot process Content-Type: "+contentType, http.StatusBadRequest)
+		return
+	}
+
+	if h.Debug {
+		Printf("rageshake report: %#v", report)
+	}
+
+	var urls []string
+	if matches := xurls.Strict().FindAllString(report.Text, -1); matches != nil && len(matches) > 0 {
+		urls = append(urls, matches...)
+	}
+
+	if len(urls) == 0 {
+		Println("no urls in rageshake report: ", report.Text)
+		http.Error(w, "no urls present in report text", http.StatusOK)
+		return
+	}
+
+	tenant, ok := h.Tenants.LookupDefault()
+	if !ok {
+		Println("no default tenant configured for rageshake submissions")
+		http.Error(w, "radar is not configured to accept rageshake submissions", http.StatusInternalServerError)
+		return
+	}
+
+	added := 0
+	for _, url := range urls {
+		if !h.Tenants.Allow(tenant.ID) {
+			Printf("rate limit exceeded for tenant %s, dropping %s", tenant.ID, url)
+			continue
+		}
+
+		req := createRequest{
+			fromEmail: report.UserAgent,
+			subject:   "Matrix rageshake",
+			url:       url,
+			tenantID:  tenant.ID,
+			reply:     func(message string) { Printf("rageshake submission result: %s", message) },
+		}
+
+		if err := h.Queue.Enqueue(r.Context(), req); err != nil {
+			Printf("could not durably enqueue %s: %#v", url, err)
+			http.Error(w, "could not save "+url+" to the radar", http.StatusInternalServerError)
+			return
+		}
+		added++
+	}
+
+	http.Error(w, fmt.Sprintf("added %d urls to today's radar", added), http.StatusCreated)
+}
@@ -0,0 +1,99 @@
+package radar
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeQueueBackend is a minimal Backend that only supports Enqueue, for
+// tests that drive a handler's ServeHTTP without running Queue.Start.
+type fakeQueueBackend struct {
+	enqueued []createRequest
+}
+
+func (b *fakeQueueBackend) Enqueue(ctx context.Context, req createRequest) (string, error) {
+	b.enqueued = append(b.enqueued, req)
+	return "1", nil
+}
+
+func (b *fakeQueueBackend) Dequeue(ctx context.Context) (createRequest, string, error) {
+	return createRequest{}, "", context.Canceled
+}
+func (b *fakeQueueBackend) Ack(ctx context.Context, deliveryID string) error        { return nil }
+func (b *fakeQueueBackend) Nack(ctx context.Context, deliveryID string) error       { return nil }
+func (b *fakeQueueBackend) DeadLetter(ctx context.Context, deliveryID string) error { return nil }
+func (b *fakeQueueBackend) Close() error                                            { return nil }
+
+func newTestRageshakeHandler(t *testing.T) (RageshakeHandler, *fakeQueueBackend) {
+	t.Helper()
+	backend := &fakeQueueBackend{}
+	queue := NewQueue(backend, nil, nil)
+	tenants := NewTenantDirectory([]*Tenant{{ID: DefaultTenantID}})
+	return NewRageshakeHandler(queue, tenants, false), backend
+}
+
+func TestRageshakeHandlerJSON(t *testing.T) {
+	h, backend := newTestRageshakeHandler(t)
+
+	body := strings.NewReader(`{"text":"check out https://example.com/report","user_agent":"element/1.2.3"}`)
+	r := httptest.NewRequest(http.MethodPost, "/rageshake", body)
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if len(backend.enqueued) != 1 {
+		t.Fatalf("expected 1 enqueued request, got %d", len(backend.enqueued))
+	}
+	if got := backend.enqueued[0].url; got != "https://example.com/report" {
+		t.Errorf("url = %q, want https://example.com/report", got)
+	}
+	if got := backend.enqueued[0].tenantID; got != DefaultTenantID {
+		t.Errorf("tenantID = %q, want %q", got, DefaultTenantID)
+	}
+}
+
+func TestRageshakeHandlerMultipart(t *testing.T) {
+	h, backend := newTestRageshakeHandler(t)
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("text", "check out https://example.com/multipart-report")
+	mw.WriteField("user_agent", "element/1.2.3")
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/rageshake", &buf)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if len(backend.enqueued) != 1 {
+		t.Fatalf("expected 1 enqueued request, got %d", len(backend.enqueued))
+	}
+	if got := backend.enqueued[0].url; got != "https://example.com/multipart-report" {
+		t.Errorf("url = %q, want https://example.com/multipart-report", got)
+	}
+}
+
+func TestRageshakeHandlerUnsupportedContentType(t *testing.T) {
+	h, backend := newTestRageshakeHandler(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/rageshake", strings.NewReader("text=https://example.com"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if len(backend.enqueued) != 0 {
+		t.Errorf("expected nothing enqueued, got %d", len(backend.enqueued))
+	}
+}
@@ -0,0 +1,166 @@
+package radar
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mvdan.cc/xurls/v2"
+)
+
+// NewSlackHandler creates a SlackHandler that enqueues onto queue.
+// signingSecret is the Slack app's signing secret, used to verify the
+// X-Slack-Signature header on every slash-command request. tenants must
+// have a Tenant registered under DefaultTenantID, since slash commands
+// carry no sender address to resolve one from.
+func NewSlackHandler(queue *Queue, signingSecret string, tenants *TenantDirectory, debug bool) SlackHandler {
+	return SlackHandler{
+		SigningSecret: signingSecret,
+		Queue:         queue,
+		Tenants:       tenants,
+		Debug:         debug,
+	}
+}
+
+// SlackHandler accepts Slack slash-command submissions (e.g.
+// "/radar https://example.com") and enqueues any URLs found in the
+// command text, replying asynchronously via the command's response_url.
+type SlackHandler struct {
+	// The Slack app's signing secret, used to verify X-Slack-Signature.
+	SigningSecret string
+
+	// The shared ingestion queue.
+	Queue *Queue
+
+	// Tenants resolves the DefaultTenantID tenant and enforces its rate
+	// limit, since slash commands carry no sender address of their own.
+	Tenants *TenantDirectory
+
+	// Enable debug logging.
+	Debug bool
+}
+
+func (h SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		Println("could not read slack request body:", err)
+		http.Error(w, "could not read request body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !h.isValidSignature(r, body) {
+		Println("invalid X-Slack-Signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		Println("could not parse slack form:", err)
+		http.Error(w, "could not parse form", http.StatusBadRequest)
+		return
+	}
+
+	text := r.FormValue("text")
+	responseURL := r.FormValue("response_url")
+	if h.Debug {
+		Printf("slack text: %#v", text)
+	}
+
+	var urls []string
+	if matches := xurls.Strict().FindAllString(text, -1); matches != nil && len(matches) > 0 {
+		urls = append(urls, matches...)
+	}
+
+	if len(urls) == 0 {
+		Println("no urls in slack command text: ", text)
+		respondToSlackCommand(w, "no urls found in that command")
+		return
+	}
+
+	tenant, ok := h.Tenants.LookupDefault()
+	if !ok {
+		Println("no default tenant configured for slack submissions")
+		http.Error(w, "radar is not configured to accept slack submissions", http.StatusInternalServerError)
+		return
+	}
+
+	added := 0
+	for _, url := range urls {
+		if !h.Tenants.Allow(tenant.ID) {
+			Printf("rate limit exceeded for tenant %s, dropping %s", tenant.ID, url)
+			continue
+		}
+
+		req := createRequest{
+			fromEmail: r.FormValue("user_name"),
+			messageID: r.FormValue("trigger_id"),
+			subject:   "Slack slash-command",
+			url:       url,
+			tenantID:  tenant.ID,
+		}
+		req.reply = func(message string) { h.replyViaResponseURL(responseURL, message) }
+
+		if err := h.Queue.Enqueue(r.Context(), req); err != nil {
+			Printf("could not durably enqueue %s: %#v", url, err)
+			http.Error(w, "could not save "+url+" to the radar", http.StatusInternalServerError)
+			return
+		}
+		added++
+	}
+
+	respondToSlackCommand(w, fmt.Sprintf("added %d urls to today's radar", added))
+}
+
+// isValidSignature checks the request's timestamp and X-Slack-Signature
+// header against the documented v0 HMAC scheme:
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func (h SlackHandler) isValidSignature(r *http.Request, body []byte) bool {
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		Printf("invalid X-Slack-Request-Timestamp %q: %#v", timestamp, err)
+		return false
+	}
+	if time.Since(time.Unix(ts, 0)) > 5*time.Minute {
+		Println("stale slack request timestamp:", timestamp)
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.SigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+func (h SlackHandler) replyViaResponseURL(responseURL, message string) {
+	if responseURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		Printf("could not marshal slack reply: %#v", err)
+		return
+	}
+
+	resp, err := http.Post(responseURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		Printf("could not post to slack response_url: %#v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func respondToSlackCommand(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"text": text})
+}
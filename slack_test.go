@@ -0,0 +1,83 @@
+package radar
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackHandlerIsValidSignature(t *testing.T) {
+	const secret = "shhh"
+	body := []byte("token=abc&text=https://example.com")
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		want      bool
+	}{
+		{
+			name:      "valid signature",
+			secret:    secret,
+			timestamp: now,
+			signature: signSlackRequest(secret, now, body),
+			want:      true,
+		},
+		{
+			name:      "wrong secret",
+			secret:    secret,
+			timestamp: now,
+			signature: signSlackRequest("wrong-secret", now, body),
+			want:      false,
+		},
+		{
+			name:      "stale timestamp",
+			secret:    secret,
+			timestamp: stale,
+			signature: signSlackRequest(secret, stale, body),
+			want:      false,
+		},
+		{
+			name:      "missing timestamp",
+			secret:    secret,
+			timestamp: "",
+			signature: signSlackRequest(secret, now, body),
+			want:      false,
+		},
+		{
+			name:      "garbage signature",
+			secret:    secret,
+			timestamp: now,
+			signature: "v0=not-a-real-signature",
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/slack", nil)
+			r.Header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			r.Header.Set("X-Slack-Signature", tt.signature)
+
+			h := SlackHandler{SigningSecret: tt.secret}
+			if got := h.isValidSignature(r, body); got != tt.want {
+				t.Errorf("isValidSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
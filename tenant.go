@@ -0,0 +1,130 @@
+package radar
+
+import (
+	"sync"
+	"time"
+)
+
+// Tenant configures one user (or small group) sharing a radar
+// deployment: where their daily digest gets published, how they're
+// mentioned in it, and how many submissions they're allowed per hour.
+type Tenant struct {
+	// ID uniquely identifies this tenant and is also used to scope
+	// storage reads/writes. Set it to the tenant's verified sender
+	// email address.
+	ID string
+
+	// Publisher is where this tenant's daily digest gets published.
+	Publisher Publisher
+
+	// Finder locates this tenant's previous radar issue, if any. May be
+	// nil if old-issue carryover isn't wanted.
+	Finder PreviousRadarFinder
+
+	// Mention is appended as a "/cc" footer on this tenant's digest.
+	Mention string
+
+	// RateLimit caps how many submissions this tenant may enqueue per
+	// hour. Zero means unlimited.
+	RateLimit int
+}
+
+// DefaultTenantID is the Tenant.ID that submission sources with no sender
+// address to resolve a tenant from (Slack, rageshake, Mastodon) are
+// routed to. A deployment that accepts those sources must register a
+// Tenant with this ID.
+const DefaultTenantID = "default"
+
+// TenantDirectory resolves a verified sender address to its Tenant and
+// enforces each tenant's rate limit.
+type TenantDirectory struct {
+	tenants map[string]*Tenant
+	buckets map[string]*tokenBucket
+}
+
+// NewTenantDirectory builds a TenantDirectory from tenants, keyed by each
+// Tenant's ID.
+func NewTenantDirectory(tenants []*Tenant) *TenantDirectory {
+	d := &TenantDirectory{
+		tenants: make(map[string]*Tenant, len(tenants)),
+		buckets: make(map[string]*tokenBucket, len(tenants)),
+	}
+
+	for _, tenant := range tenants {
+		d.tenants[tenant.ID] = tenant
+		if tenant.RateLimit > 0 {
+			d.buckets[tenant.ID] = newTokenBucket(tenant.RateLimit)
+		}
+	}
+
+	return d
+}
+
+// Lookup returns the tenant registered for email, and whether one was
+// found.
+func (d *TenantDirectory) Lookup(email string) (*Tenant, bool) {
+	tenant, ok := d.tenants[email]
+	return tenant, ok
+}
+
+// LookupDefault returns the tenant registered under DefaultTenantID, for
+// submission sources that have no sender address to resolve a tenant
+// from.
+func (d *TenantDirectory) LookupDefault() (*Tenant, bool) {
+	return d.Lookup(DefaultTenantID)
+}
+
+// Allow reports whether tenantID may make another submission right now,
+// consuming one unit of its rate limit if so. Tenants without a
+// configured RateLimit are always allowed.
+func (d *TenantDirectory) Allow(tenantID string) bool {
+	bucket, ok := d.buckets[tenantID]
+	if !ok {
+		return true
+	}
+	return bucket.Allow()
+}
+
+// tokenBucket is a simple hourly token-bucket rate limiter.
+type tokenBucket struct {
+	mu        sync.Mutex
+	tokens    float64
+	max       float64
+	rate      float64 // tokens replenished per second
+	updatedAt time.Time
+}
+
+func newTokenBucket(perHour int) *tokenBucket {
+	return &tokenBucket{
+		tokens:    float64(perHour),
+		max:       float64(perHour),
+		rate:      float64(perHour) / time.Hour.Seconds(),
+		updatedAt: time.Now(),
+	}
+}
+
+// Allow reports whether a token is available, consuming it if so.
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if elapsed := now.Sub(b.updatedAt).Seconds(); elapsed > 0 {
+		b.tokens = min(b.max, b.tokens+elapsed*b.rate)
+		b.updatedAt = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}